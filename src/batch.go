@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	s3controltypes "github.com/aws/aws-sdk-go-v2/service/s3control/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"golang.org/x/time/rate"
+)
+
+// batchPollInterval controls how often DescribeJob is polled while an S3
+// Batch Operations restore job is running. Batch jobs run for much longer
+// than a single object's restorePollInterval, so this is coarser.
+const batchPollInterval = 1 * time.Minute
+
+// BatchRestoreConfig holds the settings the --backend=batch path needs on
+// top of tier/ttl: where to stage the generated manifest (or an existing
+// manifest to reuse) and which role the Batch Operations job itself
+// assumes to call RestoreObject on every key.
+type BatchRestoreConfig struct {
+	StagingBucket     string
+	InventoryManifest string // s3://bucket/key of an existing "bucket,key" CSV manifest; empty to generate one. S3 Inventory report (manifest.json) format is not supported.
+	BatchRoleArn      string
+}
+
+// ensureBatchJobsTable creates the batch_jobs table, keyed like the
+// restore_objects table from the inline backend: one row per (request_id,
+// bucket_path), since a single request normally carries several
+// comma-separated bucket paths, each submitted as its own S3 Batch
+// Operations job.
+func ensureBatchJobsTable(db *sql.DB) error {
+	createTableQuery := `
+	CREATE TABLE IF NOT EXISTS batch_jobs (
+		request_id TEXT,
+		bucket_path TEXT,
+		job_id TEXT,
+		PRIMARY KEY (request_id, bucket_path)
+	)`
+	_, err := db.Exec(createTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create batch_jobs table: %w", err)
+	}
+	return nil
+}
+
+// recordBatchJobID upserts the JobId created for (requestID, bucketPath).
+func recordBatchJobID(requestID, bucketPath, jobID string) error {
+	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := ensureBatchJobsTable(db); err != nil {
+		return err
+	}
+
+	upsertQuery := `
+	INSERT INTO batch_jobs (request_id, bucket_path, job_id) VALUES (?, ?, ?)
+	ON CONFLICT(request_id, bucket_path) DO UPDATE SET job_id = excluded.job_id`
+	_, err = db.Exec(upsertQuery, requestID, bucketPath, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to record batch job id: %w", err)
+	}
+	return nil
+}
+
+// loadBatchJobID returns the JobId previously recorded for (requestID,
+// bucketPath), or "" if none exists yet, so --resume can reattach this
+// specific bucket path to its own in-flight batch job instead of
+// resubmitting it or clobbering another path's job ID.
+func loadBatchJobID(requestID, bucketPath string) (string, error) {
+	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
+	if err != nil {
+		return "", fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := ensureBatchJobsTable(db); err != nil {
+		return "", err
+	}
+
+	var jobID sql.NullString
+	err = db.QueryRow("SELECT job_id FROM batch_jobs WHERE request_id = ? AND bucket_path = ?", requestID, bucketPath).Scan(&jobID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load batch job id: %w", err)
+	}
+	return jobID.String, nil
+}
+
+func parseS3URI(uri string) (bucket, key string, err error) {
+	trimmed := strings.TrimPrefix(uri, "s3://")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid S3 URI %q, expected s3://bucket/key", uri)
+	}
+	return parts[0], parts[1], nil
+}
+
+// generateManifestCSV lists every object under prefix that needs a Glacier
+// restore and renders it as an S3 Batch Operations CSV manifest line
+// ("bucket,key"). Other non-STANDARD classes (STANDARD_IA, ONEZONE_IA, ...)
+// are already readable and aren't Glacier restore candidates, so including
+// them would only make S3InitiateRestoreObject fail with InvalidObjectState
+// for the whole batch job.
+func generateManifestCSV(ctx context.Context, requestID string, svc *s3.Client, bucketName, prefix string, limiter *rate.Limiter) ([]byte, error) {
+	var manifest bytes.Buffer
+
+	paginator := s3.NewListObjectsV2Paginator(svc, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		var page *s3.ListObjectsV2Output
+		err := withRetry(ctx, requestID, limiter, func() error {
+			var err error
+			page, err = paginator.NextPage(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects under %s/%s: %w", bucketName, prefix, err)
+		}
+		for _, obj := range page.Contents {
+			if needsGlacierRestore(obj.StorageClass) {
+				fmt.Fprintf(&manifest, "%s,%s\n", bucketName, *obj.Key)
+			}
+		}
+	}
+
+	return manifest.Bytes(), nil
+}
+
+// resolveManifest returns the bucket/key/ETag of the manifest the batch job
+// should read from: either the caller-supplied CSV manifest, or one
+// generated from a live listing and uploaded to the staging bucket. Either
+// way the job reads it as an S3 Batch Operations CSV with bucket,key columns;
+// an S3 Inventory report manifest is not read in its own format.
+func resolveManifest(ctx context.Context, svc *s3.Client, bucketName, prefix, requestID, bucketPath string, cfg BatchRestoreConfig, limiter *rate.Limiter) (manifestBucket, manifestKey, manifestETag string, err error) {
+	if cfg.InventoryManifest != "" {
+		manifestBucket, manifestKey, err = parseS3URI(cfg.InventoryManifest)
+		if err != nil {
+			return "", "", "", err
+		}
+		var head *s3.HeadObjectOutput
+		err = withRetry(ctx, requestID, limiter, func() error {
+			var err error
+			head, err = svc.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(manifestBucket), Key: aws.String(manifestKey)})
+			return err
+		})
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to read inventory manifest %s: %w", cfg.InventoryManifest, err)
+		}
+		return manifestBucket, manifestKey, strings.Trim(aws.ToString(head.ETag), `"`), nil
+	}
+
+	if cfg.StagingBucket == "" {
+		return "", "", "", fmt.Errorf("--staging-bucket is required for --backend=batch unless --inventory-manifest is set")
+	}
+
+	body, err := generateManifestCSV(ctx, requestID, svc, bucketName, prefix, limiter)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	manifestKey = fmt.Sprintf("s3-restore-manifests/%s/%s.csv", requestID, strings.ReplaceAll(bucketPath, "/", "_"))
+	var put *s3.PutObjectOutput
+	err = withRetry(ctx, requestID, limiter, func() error {
+		var err error
+		put, err = svc.PutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(cfg.StagingBucket),
+			Key:    aws.String(manifestKey),
+			Body:   bytes.NewReader(body),
+		})
+		return err
+	})
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to upload manifest to %s/%s: %w", cfg.StagingBucket, manifestKey, err)
+	}
+
+	return cfg.StagingBucket, manifestKey, strings.Trim(aws.ToString(put.ETag), `"`), nil
+}
+
+func accountIDFromSTS(ctx context.Context, requestID string, stsSvc *sts.Client, limiter *rate.Limiter) (string, error) {
+	var out *sts.GetCallerIdentityOutput
+	err := withRetry(ctx, requestID, limiter, func() error {
+		var err error
+		out, err = stsSvc.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve AWS account ID: %w", err)
+	}
+	return aws.ToString(out.Account), nil
+}
+
+// batchGlacierTier maps the CLI's --tier value (Bulk/Standard/Expedited,
+// matching s3.types.Tier and used as-is by the inline backend) onto the
+// all-caps, Expedited-less subset S3 Batch Operations accepts
+// (s3control.types.S3GlacierJobTier is only BULK/STANDARD).
+func batchGlacierTier(tier string) (s3controltypes.S3GlacierJobTier, error) {
+	switch strings.ToUpper(tier) {
+	case string(s3controltypes.S3GlacierJobTierBulk):
+		return s3controltypes.S3GlacierJobTierBulk, nil
+	case string(s3controltypes.S3GlacierJobTierStandard):
+		return s3controltypes.S3GlacierJobTierStandard, nil
+	default:
+		return "", fmt.Errorf("--backend=batch does not support --tier=%s; S3 Batch Operations only supports Bulk or Standard", tier)
+	}
+}
+
+func createBatchRestoreJob(ctx context.Context, requestID string, ctrlSvc *s3control.Client, accountID, roleArn, manifestBucket, manifestKey, manifestETag, tier string, ttl int, limiter *rate.Limiter) (string, error) {
+	glacierTier, err := batchGlacierTier(tier)
+	if err != nil {
+		return "", err
+	}
+
+	var out *s3control.CreateJobOutput
+	err = withRetry(ctx, requestID, limiter, func() error {
+		var err error
+		out, err = ctrlSvc.CreateJob(ctx, &s3control.CreateJobInput{
+			AccountId:            aws.String(accountID),
+			RoleArn:              aws.String(roleArn),
+			Priority:             aws.Int32(10),
+			ConfirmationRequired: aws.Bool(false),
+			Operation: &s3controltypes.JobOperation{
+				S3InitiateRestoreObject: &s3controltypes.S3InitiateRestoreObjectOperation{
+					ExpirationInDays: aws.Int32(int32(ttl)),
+					GlacierJobTier:   glacierTier,
+				},
+			},
+			Manifest: &s3controltypes.JobManifest{
+				Spec: &s3controltypes.JobManifestSpec{
+					Format: s3controltypes.JobManifestFormatS3BatchOperationsCsv20180820,
+					Fields: []s3controltypes.JobManifestFieldName{
+						s3controltypes.JobManifestFieldNameBucket,
+						s3controltypes.JobManifestFieldNameKey,
+					},
+				},
+				Location: &s3controltypes.JobManifestLocation{
+					ObjectArn: aws.String(fmt.Sprintf("arn:aws:s3:::%s/%s", manifestBucket, manifestKey)),
+					ETag:      aws.String(manifestETag),
+				},
+			},
+			Report: &s3controltypes.JobReport{
+				Enabled: false,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create S3 Batch Operations job: %w", err)
+	}
+	return aws.ToString(out.JobId), nil
+}
+
+func pollBatchJob(ctx context.Context, requestID string, ctrlSvc *s3control.Client, accountID, jobID string, limiter *rate.Limiter) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var out *s3control.DescribeJobOutput
+		err := withRetry(ctx, requestID, limiter, func() error {
+			var err error
+			out, err = ctrlSvc.DescribeJob(ctx, &s3control.DescribeJobInput{
+				AccountId: aws.String(accountID),
+				JobId:     aws.String(jobID),
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe batch job %s: %w", jobID, err)
+		}
+
+		status := out.Job.Status
+		rlog(requestID).Info().Str("job_id", jobID).Str("status", string(status)).Msg("batch restore job status")
+
+		switch status {
+		case s3controltypes.JobStatusComplete:
+			return nil
+		case s3controltypes.JobStatusFailed, s3controltypes.JobStatusCancelled:
+			return fmt.Errorf("batch job %s ended with status %s", jobID, status)
+		}
+
+		select {
+		case <-time.After(batchPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// restoreObjectsInPathBatch is the --backend=batch counterpart to
+// restoreObjectsInPath. Instead of restoring objects one at a time from this
+// process, it hands the whole prefix off to an S3 Batch Operations job and
+// polls DescribeJob for completion, which scales to prefixes with millions
+// of keys where per-object RestoreObject/CopyObject calls would be far too
+// slow. The JobId is persisted in the batch_jobs table per (request_id,
+// bucket_path) so --resume reattaches to an already-running job instead of
+// creating a duplicate one.
+func restoreObjectsInPathBatch(ctx context.Context, bucketPath, region, requestID, tier string, ttl int, cfg BatchRestoreConfig, cc ConcurrencyConfig, notifier Notifier, failedPaths chan<- string, wg *sync.WaitGroup, ch chan struct{}) {
+	defer wg.Done()
+
+	ch <- struct{}{}
+	defer func() { <-ch }()
+
+	rlog(requestID).Info().Str("bucket_path", bucketPath).Msg("starting batch restore for bucket path")
+	parts := strings.SplitN(bucketPath, "/", 2)
+	if len(parts) < 2 {
+		rlog(requestID).Error().Str("bucket_path", bucketPath).Msg("invalid bucket path")
+		failedPaths <- bucketPath
+		return
+	}
+	bucketName, prefix := parts[0], parts[1]
+
+	awsCfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credsProvider),
+	)
+	if err != nil {
+		rlog(requestID).Error().Err(err).Msg("failed to load AWS config")
+		failedPaths <- bucketPath
+		return
+	}
+
+	svc := s3.NewFromConfig(awsCfg)
+	ctrlSvc := s3control.NewFromConfig(awsCfg)
+	stsSvc := sts.NewFromConfig(awsCfg)
+
+	accountID, err := accountIDFromSTS(ctx, requestID, stsSvc, cc.Limiter)
+	if err != nil {
+		rlog(requestID).Error().Err(err).Msg("failed to resolve AWS account ID")
+		failedPaths <- bucketPath
+		return
+	}
+
+	jobID, err := loadBatchJobID(requestID, bucketPath)
+	if err != nil {
+		rlog(requestID).Error().Err(err).Msg("failed to check for an existing batch job")
+	}
+
+	manifestStart := time.Now()
+	if jobID == "" {
+		manifestBucket, manifestKey, manifestETag, err := resolveManifest(ctx, svc, bucketName, prefix, requestID, bucketPath, cfg, cc.Limiter)
+		if err != nil {
+			logPhase(requestID, bucketName, prefix, "batch_manifest", manifestStart, err)
+			failedPaths <- bucketPath
+			return
+		}
+		logPhase(requestID, bucketName, prefix, "batch_manifest", manifestStart, nil)
+
+		submitStart := time.Now()
+		jobID, err = createBatchRestoreJob(ctx, requestID, ctrlSvc, accountID, cfg.BatchRoleArn, manifestBucket, manifestKey, manifestETag, tier, ttl, cc.Limiter)
+		if err != nil {
+			logPhase(requestID, bucketName, prefix, "batch_submit", submitStart, err)
+			failedPaths <- bucketPath
+			return
+		}
+		logPhase(requestID, bucketName, prefix, "batch_submit", submitStart, nil)
+
+		if err := recordBatchJobID(requestID, bucketPath, jobID); err != nil {
+			rlog(requestID).Error().Err(err).Msg("failed to persist batch job id")
+		}
+
+		rlog(requestID).Info().Str("job_id", jobID).Str("bucket_path", bucketPath).Msg("submitted S3 Batch Operations job")
+	} else {
+		rlog(requestID).Info().Str("job_id", jobID).Str("bucket_path", bucketPath).Msg("reattaching to existing S3 Batch Operations job")
+	}
+
+	pollStart := time.Now()
+	if err := pollBatchJob(ctx, requestID, ctrlSvc, accountID, jobID, cc.Limiter); err != nil {
+		logPhase(requestID, bucketName, prefix, "batch_poll", pollStart, err)
+		failedPaths <- bucketPath
+		return
+	}
+	logPhase(requestID, bucketName, prefix, "batch_poll", pollStart, nil)
+
+	if err := updateProcessedPaths(requestID, bucketPath, notifier); err != nil {
+		rlog(requestID).Error().Err(err).Str("bucket_path", bucketPath).Msg("failed to update processed paths")
+		failedPaths <- bucketPath
+	}
+}
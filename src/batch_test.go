@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+
+	s3controltypes "github.com/aws/aws-sdk-go-v2/service/s3control/types"
+)
+
+func TestBatchGlacierTier(t *testing.T) {
+	cases := []struct {
+		tier    string
+		want    s3controltypes.S3GlacierJobTier
+		wantErr bool
+	}{
+		{"Bulk", s3controltypes.S3GlacierJobTierBulk, false},
+		{"Standard", s3controltypes.S3GlacierJobTierStandard, false},
+		{"BULK", s3controltypes.S3GlacierJobTierBulk, false},
+		{"Expedited", "", true},
+		{"bogus", "", true},
+	}
+	for _, c := range cases {
+		t.Run(c.tier, func(t *testing.T) {
+			got, err := batchGlacierTier(c.tier)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("batchGlacierTier(%q) = %v, nil, want an error", c.tier, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("batchGlacierTier(%q) returned unexpected error: %v", c.tier, err)
+			}
+			if got != c.want {
+				t.Errorf("batchGlacierTier(%q) = %v, want %v", c.tier, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// BotCommand is the message-processor pattern the Slack bot dispatches
+// subcommands through: each `/s3-restore <name> ...` subcommand implements
+// GetName/GetHelp/ProcessMessage and is looked up by name, the same shape
+// other Slack chat bots in this organization use for command dispatch.
+type BotCommand interface {
+	GetName() string
+	GetHelp() string
+	ProcessMessage(ctx context.Context, bot *BotServer, args []string) (string, error)
+}
+
+// BotServer backs the `serve` subcommand: a long-running process that holds
+// the in-memory JobManager and dispatches slash commands and Block Kit
+// button actions to it.
+type BotServer struct {
+	region      string
+	tier        string
+	concurrency ConcurrencyConfig
+	notifier    Notifier
+	jobs        *JobManager
+	commands    map[string]BotCommand
+}
+
+func NewBotServer(region, tier string, cc ConcurrencyConfig, notifier Notifier) *BotServer {
+	bot := &BotServer{
+		region:      region,
+		tier:        tier,
+		concurrency: cc,
+		notifier:    notifier,
+		jobs:        NewJobManager(),
+		commands:    make(map[string]BotCommand),
+	}
+	for _, cmd := range []BotCommand{submitCommand{}, statusCommand{}, cancelCommand{}} {
+		bot.commands[cmd.GetName()] = cmd
+	}
+	return bot
+}
+
+// Dispatch parses the text of a `/s3-restore` slash command and runs the
+// matching BotCommand, returning the text to reply with.
+func (bot *BotServer) Dispatch(ctx context.Context, text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return bot.help()
+	}
+
+	cmd, ok := bot.commands[fields[0]]
+	if !ok {
+		return fmt.Sprintf("Unknown command %q.\n%s", fields[0], bot.help())
+	}
+
+	reply, err := cmd.ProcessMessage(ctx, bot, fields[1:])
+	if err != nil {
+		return fmt.Sprintf(":x: %v", err)
+	}
+	return reply
+}
+
+func (bot *BotServer) help() string {
+	var help strings.Builder
+	help.WriteString("Available commands:\n")
+	for _, cmd := range bot.commands {
+		help.WriteString(fmt.Sprintf("- `%s`: %s\n", cmd.GetName(), cmd.GetHelp()))
+	}
+	return help.String()
+}
+
+type submitCommand struct{}
+
+func (submitCommand) GetName() string { return "submit" }
+func (submitCommand) GetHelp() string {
+	return "submit <bucket/path,...> [--ttl=30] - start a new restore job"
+}
+
+func (submitCommand) ProcessMessage(ctx context.Context, bot *BotServer, args []string) (string, error) {
+	ttl := 30
+	var paths []string
+
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--ttl=") {
+			parsed, err := strconv.Atoi(strings.TrimPrefix(arg, "--ttl="))
+			if err != nil {
+				return "", fmt.Errorf("invalid --ttl value: %w", err)
+			}
+			ttl = parsed
+			continue
+		}
+		paths = append(paths, strings.Split(arg, ",")...)
+	}
+
+	if len(paths) == 0 {
+		return "", fmt.Errorf("usage: submit <bucket/path,...> [--ttl=30]")
+	}
+
+	job, err := bot.jobs.Submit(ctx, bot.region, bot.tier, ttl, paths, bot.concurrency, bot.notifier)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(":rocket: Submitted restore job `%s` for %d path(s)", job.RequestID, len(paths)), nil
+}
+
+type statusCommand struct{}
+
+func (statusCommand) GetName() string { return "status" }
+func (statusCommand) GetHelp() string { return "status <request_id> - show progress for a restore job" }
+
+func (statusCommand) ProcessMessage(ctx context.Context, bot *BotServer, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: status <request_id>")
+	}
+
+	job, ok := bot.jobs.Get(args[0])
+	if !ok {
+		return "", fmt.Errorf("no in-flight job found for request ID %s", args[0])
+	}
+
+	return job.Summary(), nil
+}
+
+type cancelCommand struct{}
+
+func (cancelCommand) GetName() string { return "cancel" }
+func (cancelCommand) GetHelp() string { return "cancel <request_id> - stop an in-flight restore job" }
+
+func (cancelCommand) ProcessMessage(ctx context.Context, bot *BotServer, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: cancel <request_id>")
+	}
+
+	if !bot.jobs.Cancel(args[0]) {
+		return "", fmt.Errorf("no in-flight job found for request ID %s", args[0])
+	}
+
+	return fmt.Sprintf(":octagonal_sign: Cancelling restore job `%s`", args[0]), nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import "golang.org/x/time/rate"
+
+// ConcurrencyConfig bundles the tunables that control how aggressively a
+// restore job talks to S3: how many bucket paths and how many objects within
+// a path are processed concurrently, and the token-bucket limiter every S3
+// call waits on before it is attempted.
+type ConcurrencyConfig struct {
+	PathConcurrency   int
+	ObjectConcurrency int
+	Limiter           *rate.Limiter
+}
+
+// NewConcurrencyConfig builds a ConcurrencyConfig from the --path-concurrency,
+// --object-concurrency, and --rps flag values. rps <= 0 disables rate
+// limiting entirely.
+func NewConcurrencyConfig(pathConcurrency, objectConcurrency int, rps float64) ConcurrencyConfig {
+	limit := rate.Inf
+	burst := 1
+	if rps > 0 {
+		limit = rate.Limit(rps)
+		burst = int(rps)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return ConcurrencyConfig{
+		PathConcurrency:   pathConcurrency,
+		ObjectConcurrency: objectConcurrency,
+		Limiter:           rate.NewLimiter(limit, burst),
+	}
+}
+
+// collectFailedPaths drains ch into a slice. Bucket-path goroutines send
+// their own failures on ch instead of appending to a shared slice, so
+// nothing needs to synchronize on a mutex to avoid racing on failedPaths.
+func collectFailedPaths(ch <-chan string) []string {
+	var failedPaths []string
+	for path := range ch {
+		failedPaths = append(failedPaths, path)
+	}
+	return failedPaths
+}
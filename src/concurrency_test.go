@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/time/rate"
+)
+
+func TestNewConcurrencyConfig(t *testing.T) {
+	cc := NewConcurrencyConfig(5, 5, 50)
+	if cc.PathConcurrency != 5 || cc.ObjectConcurrency != 5 {
+		t.Errorf("got PathConcurrency=%d ObjectConcurrency=%d, want 5, 5", cc.PathConcurrency, cc.ObjectConcurrency)
+	}
+	if cc.Limiter.Limit() != rate.Limit(50) {
+		t.Errorf("Limiter.Limit() = %v, want 50", cc.Limiter.Limit())
+	}
+}
+
+func TestNewConcurrencyConfigUnlimitedRPS(t *testing.T) {
+	cc := NewConcurrencyConfig(5, 5, 0)
+	if cc.Limiter.Limit() != rate.Inf {
+		t.Errorf("Limiter.Limit() = %v, want rate.Inf for rps=0", cc.Limiter.Limit())
+	}
+}
+
+func TestCollectFailedPaths(t *testing.T) {
+	ch := make(chan string, 2)
+	ch <- "bucket/a"
+	ch <- "bucket/b"
+	close(ch)
+
+	got := collectFailedPaths(ch)
+	if len(got) != 2 {
+		t.Fatalf("collectFailedPaths() = %v, want 2 entries", got)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Job is a restore request running as a goroutine inside the `serve`
+// process. The existing restore_requests/restore_objects SQLite tables
+// remain the durable source of truth; Job only tracks in-memory state
+// (cancellation, last known status) needed to answer Slack slash commands.
+type Job struct {
+	RequestID   string
+	BucketPaths []string
+	Region      string
+	Tier        string
+	TTL         int
+	Concurrency ConcurrencyConfig
+
+	cancel context.CancelFunc
+
+	mu          sync.Mutex
+	status      string
+	failedPaths []string
+}
+
+func (j *Job) Status() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+func (j *Job) FailedPaths() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]string(nil), j.failedPaths...)
+}
+
+func (j *Job) setResult(status string, failedPaths []string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = status
+	j.failedPaths = failedPaths
+}
+
+func (j *Job) Summary() string {
+	return fmt.Sprintf("Request `%s`: status=%s, failed_paths=%d", j.RequestID, j.Status(), len(j.FailedPaths()))
+}
+
+// JobManager keeps the restore jobs started from Slack in memory, keyed by
+// RequestID, so the status/cancel slash commands and the Cancel/Retry Failed
+// Paths buttons can reach an in-flight job.
+type JobManager struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+func NewJobManager() *JobManager {
+	return &JobManager{jobs: make(map[string]*Job)}
+}
+
+// Submit creates a new restore_requests record and starts processing its
+// bucket paths as a goroutine, exactly like the one-shot CLI flow.
+func (jm *JobManager) Submit(ctx context.Context, region, tier string, ttl int, bucketPaths []string, cc ConcurrencyConfig, notifier Notifier) (*Job, error) {
+	requestID := generateRequestID()
+	if err := createDBAndRecord(requestID, bucketPaths, ttl, notifier); err != nil {
+		return nil, err
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	job := &Job{
+		RequestID:   requestID,
+		BucketPaths: bucketPaths,
+		Region:      region,
+		Tier:        tier,
+		TTL:         ttl,
+		Concurrency: cc,
+		cancel:      cancel,
+		status:      "running",
+	}
+
+	jm.mu.Lock()
+	jm.jobs[requestID] = job
+	jm.mu.Unlock()
+
+	go jm.run(jobCtx, job, notifier)
+
+	return job, nil
+}
+
+func (jm *JobManager) run(ctx context.Context, job *Job, notifier Notifier) {
+	var wg sync.WaitGroup
+	ch := make(chan struct{}, job.Concurrency.PathConcurrency)
+	failedPathsCh := make(chan string, len(job.BucketPaths))
+
+	for _, path := range job.BucketPaths {
+		wg.Add(1)
+		go restoreObjectsInPath(ctx, path, job.Region, job.RequestID, job.Tier, job.TTL, job.Concurrency, notifier, failedPathsCh, &wg, ch)
+	}
+	wg.Wait()
+	close(failedPathsCh)
+	failedPaths := collectFailedPaths(failedPathsCh)
+
+	switch {
+	case ctx.Err() != nil:
+		job.setResult("cancelled", failedPaths)
+	case len(failedPaths) > 0:
+		job.setResult("failed", failedPaths)
+	default:
+		job.setResult("completed", failedPaths)
+	}
+}
+
+func (jm *JobManager) Get(requestID string) (*Job, bool) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+	job, ok := jm.jobs[requestID]
+	return job, ok
+}
+
+// Cancel stops the goroutines for requestID by cancelling its context: each
+// bucket path stops starting new objects at its next cancellation check, and
+// in-flight S3 calls and restore polling made with that context are aborted.
+func (jm *JobManager) Cancel(requestID string) bool {
+	jm.mu.Lock()
+	job, ok := jm.jobs[requestID]
+	jm.mu.Unlock()
+	if !ok {
+		return false
+	}
+	job.cancel()
+	return true
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/rs/zerolog"
+)
+
+// requestLogFiles and requestLoggers cache the per-request log file and
+// JSON logger so every call site for a given requestID appends to the same
+// trace instead of opening a new file each time - the same "reuse what was
+// created for this request_id" pattern the SQLite helpers already follow.
+var (
+	requestLogFiles sync.Map // requestID -> *os.File
+	requestLoggers  sync.Map // requestID -> *zerolog.Logger
+)
+
+// rlog returns a structured JSON logger scoped to requestID. Every log line
+// carries a request_id field, and the first call for a given requestID also
+// opens a temp file so the whole trace can be shipped to S3 with --log-s3
+// once the request completes.
+func rlog(requestID string) *zerolog.Logger {
+	if cached, ok := requestLoggers.Load(requestID); ok {
+		return cached.(*zerolog.Logger)
+	}
+
+	var writer io.Writer = os.Stdout
+	if file, err := os.CreateTemp("", fmt.Sprintf("s3-restore-%s-*.log", requestID)); err == nil {
+		requestLogFiles.Store(requestID, file)
+		writer = io.MultiWriter(os.Stdout, file)
+	}
+
+	logger := zerolog.New(writer).With().Timestamp().Str("request_id", requestID).Logger()
+	requestLoggers.Store(requestID, &logger)
+	return &logger
+}
+
+// logPhase emits one structured line for a single restore phase (list,
+// restore_submit, restore_poll, copy, batch_manifest, batch_submit,
+// batch_poll, ...), recording the bucket/key it ran against and how long it
+// took.
+func logPhase(requestID, bucket, key, phase string, start time.Time, err error) {
+	logger := rlog(requestID)
+	var event *zerolog.Event
+	if err != nil {
+		event = logger.Error().Err(err)
+	} else {
+		event = logger.Info()
+	}
+	event.
+		Str("bucket", bucket).
+		Str("key", key).
+		Str("phase", phase).
+		Int64("duration_ms", time.Since(start).Milliseconds()).
+		Msg(phase)
+}
+
+func parseS3LogURI(uri string) (bucket, prefix string, err error) {
+	trimmed := strings.TrimPrefix(uri, "s3://")
+	if trimmed == "" {
+		return "", "", fmt.Errorf("invalid --log-s3 URI %q, expected s3://bucket/prefix", uri)
+	}
+	parts := strings.SplitN(trimmed, "/", 2)
+	bucket = parts[0]
+	if len(parts) == 2 {
+		prefix = parts[1]
+	}
+	return bucket, prefix, nil
+}
+
+// shipLogToS3 uploads the requestID's accumulated log file to logS3 (an
+// s3://bucket/prefix URI) and returns a presigned URL.
+func shipLogToS3(ctx context.Context, svc *s3.Client, logS3, requestID string) (string, error) {
+	if logS3 == "" {
+		return "", nil
+	}
+
+	fileVal, ok := requestLogFiles.Load(requestID)
+	if !ok {
+		return "", fmt.Errorf("no log file recorded for request %s", requestID)
+	}
+	file := fileVal.(*os.File)
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("failed to rewind log file for request %s: %w", requestID, err)
+	}
+
+	bucket, prefix, err := parseS3LogURI(logS3)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimPrefix(fmt.Sprintf("%s/%s.log", strings.TrimSuffix(prefix, "/"), requestID), "/")
+
+	if _, err := svc.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}); err != nil {
+		return "", fmt.Errorf("failed to upload log for request %s: %w", requestID, err)
+	}
+
+	presigned, err := s3.NewPresignClient(svc).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(7*24*time.Hour))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign log URL for request %s: %w", requestID, err)
+	}
+
+	return presigned.URL, nil
+}
+
+// shipRequestLog loads an AWS config for region and ships requestID's
+// accumulated log to logS3, returning a presigned URL. It is a no-op
+// (returning "", nil) when logS3 is empty, so --log-s3 stays entirely
+// optional.
+func shipRequestLog(ctx context.Context, region, logS3, requestID string) (string, error) {
+	if logS3 == "" {
+		return "", nil
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credsProvider),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return shipLogToS3(ctx, s3.NewFromConfig(awsCfg), logS3, requestID)
+}
+
+// logLinkFields returns the notification fields for a completion or failure
+// event: the request ID, plus a clickable link to the shipped log when
+// logURL is non-empty.
+func logLinkFields(requestID, logURL string) []KV {
+	fields := []KV{{Key: "Request ID", Value: requestID}}
+	if logURL != "" {
+		fields = append(fields, KV{Key: "Log", Value: logURL})
+	}
+	return fields
+}
+
+// closeRequestLog releases the temp file and cached logger for requestID
+// once its trace has been shipped (or the request is abandoned).
+func closeRequestLog(requestID string) {
+	if fileVal, ok := requestLogFiles.Load(requestID); ok {
+		file := fileVal.(*os.File)
+		file.Close()
+		os.Remove(file.Name())
+		requestLogFiles.Delete(requestID)
+	}
+	requestLoggers.Delete(requestID)
+}
@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -23,7 +24,6 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/go-ini/ini"
 	_ "github.com/mattn/go-sqlite3"
-	"github.com/slack-go/slack"
 )
 
 type RestoreRequest struct {
@@ -52,10 +52,7 @@ func (p *customCredentialsProvider) UpdateCredentials(newCreds aws.Credentials)
 	*p.creds = newCreds
 }
 
-var (
-	messageTimestamp string
-	credsProvider    *customCredentialsProvider
-)
+var credsProvider *customCredentialsProvider
 
 func generateRequestID() string {
 	bytes := make([]byte, 16)
@@ -66,40 +63,7 @@ func generateRequestID() string {
 	return hex.EncodeToString(bytes)
 }
 
-func sendSlackNotification(channel, threadTS string, blocks []slack.Block) error {
-	slackToken := os.Getenv("SLACK_API_TOKEN")
-	if slackToken == "" {
-		log.Println("No SLACK_API_TOKEN set. Slack messages will not be sent.")
-		return fmt.Errorf("SLACK_API_TOKEN is not set")
-	}
-
-	api := slack.New(slackToken)
-	opts := []slack.MsgOption{
-		slack.MsgOptionBlocks(blocks...),
-	}
-
-	if threadTS != "" {
-		opts = append(opts, slack.MsgOptionTS(threadTS))
-	}
-
-	if messageTimestamp != "" {
-		opts = append(opts, slack.MsgOptionUpdate(messageTimestamp))
-	}
-
-	_, newTimestamp, err := api.PostMessage(channel, opts...)
-	if err != nil {
-		log.Printf("Failed to send Slack message: %v\n", err)
-		return err
-	}
-
-	if messageTimestamp == "" {
-		messageTimestamp = newTimestamp
-	}
-
-	return nil
-}
-
-func createDBAndRecord(requestID string, bucketPaths []string, ttl int) error {
+func createDBAndRecord(requestID string, bucketPaths []string, ttl int, notifier Notifier) error {
 	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
@@ -130,50 +94,36 @@ func createDBAndRecord(requestID string, bucketPaths []string, ttl int) error {
 		return fmt.Errorf("failed to insert record: %w", err)
 	}
 
-	blocks := []slack.Block{
-		slack.NewHeaderBlock(&slack.TextBlockObject{
-			Type: slack.PlainTextType,
-			Text: ":memo: Created database record",
-		}),
-		slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: fmt.Sprintf("*Request ID:* `%s`\n*TTL:* `%d` days\n*Created At:* `%s`\n*Updated At:* `%s`\n",
-					requestID, ttl, time.Now().UTC().Format(time.RFC3339), time.Now().UTC().Format(time.RFC3339)),
-			},
-			nil,
-			nil,
-		),
-		slack.NewDividerBlock(),
-		slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: "*Bucket Paths:*",
-			},
-			nil,
-			nil,
-		),
-	}
-	for _, path := range bucketPaths {
-		blocks = append(blocks, slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: fmt.Sprintf("- `%s`", path),
-			},
-			nil,
-			nil,
-		))
+	now := time.Now().UTC().Format(time.RFC3339)
+	event := NotificationEvent{
+		RequestID: requestID,
+		Title:     ":memo: Created database record",
+		Fields: []KV{
+			{Key: "Request ID", Value: requestID},
+			{Key: "TTL", Value: fmt.Sprintf("%d days", ttl)},
+			{Key: "Created At", Value: now},
+			{Key: "Updated At", Value: now},
+		},
+		Lists: []NotificationList{
+			{Label: "Bucket Paths:", Items: bucketPaths},
+		},
+		Request: RestoreRequest{
+			RequestID:   requestID,
+			BucketPaths: bucketPaths,
+			TTL:         ttl,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
 	}
-
-	if err := sendSlackNotification(os.Getenv("SLACK_CHANNEL_ID"), os.Getenv("SLACK_THREAD_TS"), blocks); err != nil {
-		log.Printf("Error sending Slack notification: %v\n", err)
+	if err := notifier.NotifyProgress(event); err != nil {
+		rlog(requestID).Error().Err(err).Msg("failed to send notification")
 	}
 
-	log.Println("Created database record:", requestID)
+	rlog(requestID).Info().Strs("bucket_paths", bucketPaths).Msg("created database record")
 	return nil
 }
 
-func updateProcessedPaths(requestID, processedPath string) error {
+func updateProcessedPaths(requestID, processedPath string, notifier Notifier) error {
 	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
@@ -211,64 +161,30 @@ func updateProcessedPaths(requestID, processedPath string) error {
 		return fmt.Errorf("failed to update paths: %w", err)
 	}
 
-	blocks := []slack.Block{
-		slack.NewHeaderBlock(&slack.TextBlockObject{
-			Type: slack.PlainTextType,
-			Text: ":hourglass_flowing_sand: Updated database record",
-		}),
-		slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: fmt.Sprintf("*Request ID:* `%s`\n*Updated At:* `%s`\n",
-					requestID, time.Now().UTC().Format(time.RFC3339)),
-			},
-			nil,
-			nil,
-		),
-		slack.NewDividerBlock(),
-		slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: "*Remaining Bucket Paths:*",
-			},
-			nil,
-			nil,
-		),
-	}
-	for _, path := range bp {
-		blocks = append(blocks, slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: fmt.Sprintf("- `%s`", path),
-			},
-			nil,
-			nil,
-		))
-	}
-	blocks = append(blocks, slack.NewDividerBlock(), slack.NewSectionBlock(
-		&slack.TextBlockObject{
-			Type: slack.MarkdownType,
-			Text: "*Processed Paths:*",
+	now := time.Now().UTC().Format(time.RFC3339)
+	event := NotificationEvent{
+		RequestID: requestID,
+		Title:     ":hourglass_flowing_sand: Updated database record",
+		Fields: []KV{
+			{Key: "Request ID", Value: requestID},
+			{Key: "Updated At", Value: now},
+		},
+		Lists: []NotificationList{
+			{Label: "Remaining Bucket Paths:", Items: bp},
+			{Label: "Processed Paths:", Items: pp},
+		},
+		Request: RestoreRequest{
+			RequestID:      requestID,
+			BucketPaths:    bp,
+			ProcessedPaths: pp,
+			UpdatedAt:      now,
 		},
-		nil,
-		nil,
-	))
-	for _, path := range pp {
-		blocks = append(blocks, slack.NewSectionBlock(
-			&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: fmt.Sprintf("- `%s`", path),
-			},
-			nil,
-			nil,
-		))
 	}
-
-	if err := sendSlackNotification(os.Getenv("SLACK_CHANNEL_ID"), os.Getenv("SLACK_THREAD_TS"), blocks); err != nil {
-		log.Printf("Error sending Slack notification: %v\n", err)
+	if err := notifier.NotifyProgress(event); err != nil {
+		rlog(requestID).Error().Err(err).Msg("failed to send notification")
 	}
 
-	log.Println("Updated database record:", requestID)
+	rlog(requestID).Info().Str("processed_path", processedPath).Int("remaining", len(bp)).Msg("updated database record")
 
 	if len(bp) == 0 {
 		deleteQuery := "DELETE FROM restore_requests WHERE request_id = ?"
@@ -276,77 +192,63 @@ func updateProcessedPaths(requestID, processedPath string) error {
 		if err != nil {
 			return fmt.Errorf("failed to delete record: %w", err)
 		}
-		message := fmt.Sprintf(":white_check_mark: *All paths processed for Request ID:* *%s*. *Record deleted.*\n", requestID)
-		if err := sendSlackNotification(os.Getenv("SLACK_CHANNEL_ID"), os.Getenv("SLACK_THREAD_TS"), []slack.Block{
-			slack.NewSectionBlock(&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: message,
-			}, nil, nil),
-		}); err != nil {
-			log.Printf("Error sending Slack notification: %v\n", err)
+		message := fmt.Sprintf("All paths processed for Request ID: %s. Record deleted.", requestID)
+		completionEvent := NotificationEvent{
+			RequestID: requestID,
+			Title:     ":white_check_mark: " + message,
+			Fields: []KV{
+				{Key: "Request ID", Value: requestID},
+				{Key: "Processed At", Value: now},
+			},
+			Lists: []NotificationList{
+				{Label: "Processed Paths:", Items: pp},
+			},
+			Request: RestoreRequest{
+				RequestID:      requestID,
+				ProcessedPaths: pp,
+				UpdatedAt:      now,
+			},
 		}
-		fmt.Print(message)
-	}
-
-	return nil
-}
-
-func restoreObject(svc *s3.Client, bucketName, key string) error {
-	log.Printf("Attempting to restore object: %s/%s", bucketName, key)
-
-	copyInput := &s3.CopyObjectInput{
-		Bucket:       aws.String(bucketName),
-		CopySource:   aws.String(fmt.Sprintf("%s/%s", bucketName, key)),
-		Key:          aws.String(key),
-		StorageClass: "STANDARD",
-	}
-
-	_, err := svc.CopyObject(context.TODO(), copyInput)
-	if err != nil {
-		return fmt.Errorf("failed to restore object %s: %v", key, err)
-	}
-
-	// Check if the object storage class was updated successfully
-	headInput := &s3.HeadObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(key),
-	}
-	headOutput, err := svc.HeadObject(context.TODO(), headInput)
-	if err != nil {
-		return fmt.Errorf("failed to verify storage class for object %s: %v", key, err)
-	}
-
-	if headOutput.StorageClass == "" || headOutput.StorageClass != "STANDARD" {
-		return fmt.Errorf("storage class for object %s is not STANDARD, it is %v", key, headOutput.StorageClass)
+		if err := notifier.NotifyCompletion(completionEvent); err != nil {
+			rlog(requestID).Error().Err(err).Msg("failed to send notification")
+		}
+		rlog(requestID).Info().Msg(message)
 	}
 
-	log.Printf("Object %s restored to STANDARD storage class\n", key)
 	return nil
 }
 
-func restoreObjectsInPath(bucketPath, region, requestID string, failedPaths *[]string, wg *sync.WaitGroup, ch chan struct{}) {
+// restoreObjectsInPath lists and restores every non-STANDARD object under
+// bucketPath. Up to cc.ObjectConcurrency goroutines submit restores at once,
+// and every S3 call they make shares cc.Limiter so the combined request
+// rate across all of them stays under --rps. Once a Glacier restore is
+// submitted, waiting for it to finish and copying the object back to
+// STANDARD runs in its own unbounded goroutine rather than holding an
+// object-concurrency slot, since that wait can take hours for Bulk/Standard
+// tier retrievals.
+func restoreObjectsInPath(ctx context.Context, bucketPath, region, requestID, tier string, ttl int, cc ConcurrencyConfig, notifier Notifier, failedPaths chan<- string, wg *sync.WaitGroup, ch chan struct{}) {
 	defer wg.Done()
 
 	// Acquire a slot
 	ch <- struct{}{}
 	defer func() { <-ch }()
 
-	log.Printf("Starting to process bucket path: %s\n", bucketPath)
+	rlog(requestID).Info().Str("bucket_path", bucketPath).Msg("starting to process bucket path")
 	parts := strings.SplitN(bucketPath, "/", 2)
 	if len(parts) < 2 {
-		log.Printf("Invalid bucket path: %s\n", bucketPath)
-		*failedPaths = append(*failedPaths, bucketPath)
+		rlog(requestID).Error().Str("bucket_path", bucketPath).Msg("invalid bucket path")
+		failedPaths <- bucketPath
 		return
 	}
 	bucketName, prefix := parts[0], parts[1]
 
-	cfg, err := config.LoadDefaultConfig(context.TODO(),
+	cfg, err := config.LoadDefaultConfig(ctx,
 		config.WithRegion(region),
 		config.WithCredentialsProvider(credsProvider),
 	)
 	if err != nil {
-		log.Printf("Failed to load AWS config: %v\n", err)
-		*failedPaths = append(*failedPaths, bucketPath)
+		rlog(requestID).Error().Err(err).Msg("failed to load AWS config")
+		failedPaths <- bucketPath
 		return
 	}
 
@@ -357,33 +259,93 @@ func restoreObjectsInPath(bucketPath, region, requestID string, failedPaths *[]s
 		Prefix: aws.String(prefix),
 	}
 
+	listStart := time.Now()
 	paginator := s3.NewListObjectsV2Paginator(svc, params)
 
+	objCh := make(chan struct{}, cc.ObjectConcurrency)
+	var objWg sync.WaitGroup
+	var finishWg sync.WaitGroup
+	var failedObjects int32
+
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		if ctx.Err() != nil {
+			rlog(requestID).Info().Str("bucket_path", bucketPath).Msg("restore cancelled while processing bucket path")
+			objWg.Wait()
+			finishWg.Wait()
+			return
+		}
+
+		var page *s3.ListObjectsV2Output
+		err := withRetry(ctx, requestID, cc.Limiter, func() error {
+			var err error
+			page, err = paginator.NextPage(ctx)
+			return err
+		})
 		if err != nil {
-			log.Printf("Failed to list objects for bucket path %s: %v\n", bucketPath, err)
-			*failedPaths = append(*failedPaths, bucketPath)
+			logPhase(requestID, bucketName, prefix, "list", listStart, err)
+			objWg.Wait()
+			finishWg.Wait()
+			failedPaths <- bucketPath
 			return
 		}
 
 		for _, obj := range page.Contents {
+			if ctx.Err() != nil {
+				rlog(requestID).Info().Str("bucket_path", bucketPath).Msg("restore cancelled while processing bucket path")
+				objWg.Wait()
+				finishWg.Wait()
+				return
+			}
 			if obj.StorageClass != types.ObjectStorageClassStandard {
-				err := restoreObject(svc, bucketName, *obj.Key)
-				if err != nil {
-					log.Printf("Error restoring object %s: %v\n", *obj.Key, err)
-					continue
-				}
-				// Wait for a few seconds to ensure the object is processed before moving on to the next
-				time.Sleep(2 * time.Second)
+				key := *obj.Key
+				glacier := needsGlacierRestore(obj.StorageClass)
+				objWg.Add(1)
+				objCh <- struct{}{}
+				go func() {
+					defer objWg.Done()
+					defer func() { <-objCh }()
+
+					if !glacier {
+						if err := copyObjectToStandard(ctx, svc, requestID, bucketName, key, cc.Limiter); err != nil {
+							atomic.AddInt32(&failedObjects, 1)
+							rlog(requestID).Error().Err(err).Str("bucket", bucketName).Str("key", key).Msg("error restoring object")
+						}
+						return
+					}
+
+					if err := submitGlacierRestore(ctx, svc, requestID, bucketName, key, tier, ttl, cc.Limiter); err != nil {
+						atomic.AddInt32(&failedObjects, 1)
+						rlog(requestID).Error().Err(err).Str("bucket", bucketName).Str("key", key).Msg("error submitting restore for object")
+						return
+					}
+
+					finishWg.Add(1)
+					go func() {
+						defer finishWg.Done()
+						if err := finishGlacierRestore(ctx, svc, requestID, bucketName, key, cc.Limiter); err != nil {
+							atomic.AddInt32(&failedObjects, 1)
+							rlog(requestID).Error().Err(err).Str("bucket", bucketName).Str("key", key).Msg("error restoring object")
+						}
+					}()
+				}()
 			}
 		}
 	}
+	objWg.Wait()
+	finishWg.Wait()
+	logPhase(requestID, bucketName, prefix, "list", listStart, nil)
+
+	if n := atomic.LoadInt32(&failedObjects); n > 0 {
+		rlog(requestID).Error().Str("bucket_path", bucketPath).Int32("failed_objects", n).
+			Msg("one or more objects failed to restore, leaving bucket path unprocessed for --resume")
+		failedPaths <- bucketPath
+		return
+	}
 
-	err = updateProcessedPaths(requestID, bucketPath)
+	err = updateProcessedPaths(requestID, bucketPath, notifier)
 	if err != nil {
-		log.Printf("Failed to update processed paths for Request ID %s: %v\n", requestID, err)
-		*failedPaths = append(*failedPaths, bucketPath)
+		rlog(requestID).Error().Err(err).Str("bucket_path", bucketPath).Msg("failed to update processed paths")
+		failedPaths <- bucketPath
 	}
 }
 
@@ -445,66 +407,153 @@ func renewCredentials(roleArn, region string) {
 	}
 }
 
+// initCredentials assumes the profile's configured IAM role, wires the
+// result into the package-level credsProvider, and starts the background
+// renewal loop. Shared by the one-shot restore flow and `serve`.
+func initCredentials(profile, region string) error {
+	roleArn, err := getRoleArnFromProfile(profile)
+	if err != nil {
+		return fmt.Errorf("failed to get role ARN from profile: %w", err)
+	}
+
+	initialCreds, err := assumeRole(roleArn, region)
+	if err != nil {
+		return fmt.Errorf("failed to assume role: %w", err)
+	}
+
+	credsProvider = &customCredentialsProvider{creds: &initialCreds}
+
+	go renewCredentials(roleArn, region)
+
+	return nil
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		runServeCommand(os.Args[2:])
+		return
+	}
+
 	bucketPaths := flag.String("bucket_paths", "", "Comma-separated list of S3 bucket paths to restore")
 	region := flag.String("region", "", "AWS region")
 	ttl := flag.Int("ttl", 30, "Time-to-live (TTL) in days for restored objects before reverting to original storage class")
+	tier := flag.String("tier", "Standard", "Glacier restore tier to request (Bulk, Standard, or Expedited)")
 	profile := flag.String("profile", "default", "AWS profile to use")
+	resume := flag.String("resume", "", "Resume a previous request ID instead of starting a new restore")
+	notifierSpec := flag.String("notifier", "slack", "Comma-separated notification sinks to use (slack, teams, webhook, stdout)")
+	backend := flag.String("backend", "inline", "Restore backend to use: inline (per-object) or batch (S3 Batch Operations)")
+	stagingBucket := flag.String("staging-bucket", "", "S3 bucket to stage generated manifests in for --backend=batch")
+	inventoryManifest := flag.String("inventory-manifest", "", "s3://bucket/key of an existing bucket,key CSV manifest to reuse for --backend=batch (S3 Inventory report format is not supported)")
+	batchRoleArn := flag.String("batch-role-arn", "", "IAM role ARN the S3 Batch Operations job assumes for --backend=batch")
+	logS3 := flag.String("log-s3", "", "s3://bucket/prefix to stream this request's structured log to on completion")
+	pathConcurrency := flag.Int("path-concurrency", 5, "Number of bucket paths to process concurrently")
+	objectConcurrency := flag.Int("object-concurrency", 5, "Number of objects to restore concurrently within a single bucket path")
+	rps := flag.Float64("rps", 50, "Maximum combined S3 requests per second across all in-flight paths/objects; 0 disables the limit")
 	flag.Parse()
 
-	if *bucketPaths == "" {
+	if *resume == "" && *bucketPaths == "" {
 		log.Fatal("Please provide bucket paths")
 	}
 	if *region == "" {
 		log.Fatal("Please provide an AWS region")
 	}
+	if *backend != "inline" && *backend != "batch" {
+		log.Fatalf("Unknown backend %q, expected inline or batch", *backend)
+	}
+	if err := validateTier(*tier); err != nil {
+		log.Fatal(err)
+	}
 
-	roleArn, err := getRoleArnFromProfile(*profile)
+	notifier, err := newNotifier(*notifierSpec)
 	if err != nil {
-		log.Fatalf("Failed to get role ARN from profile: %v", err)
+		log.Fatalf("Failed to configure notifier: %v", err)
 	}
 
-	initialCreds, err := assumeRole(roleArn, *region)
-	if err != nil {
-		log.Fatalf("Failed to assume role: %v", err)
+	batchCfg := BatchRestoreConfig{
+		StagingBucket:     *stagingBucket,
+		InventoryManifest: *inventoryManifest,
+		BatchRoleArn:      *batchRoleArn,
 	}
 
-	credsProvider = &customCredentialsProvider{creds: &initialCreds}
+	if err := initCredentials(*profile, *region); err != nil {
+		log.Fatal(err)
+	}
 
-	go renewCredentials(roleArn, *region)
+	cc := NewConcurrencyConfig(*pathConcurrency, *objectConcurrency, *rps)
 
-	requestID := generateRequestID()
-	bucketPathsList := strings.Split(*bucketPaths, ",")
-	var failedPaths []string
+	var requestID string
+	var bucketPathsList []string
 
-	err = createDBAndRecord(requestID, bucketPathsList, *ttl)
-	if err != nil {
-		log.Fatalf("Failed to create DB record: %v\n", err)
+	if *resume != "" {
+		requestID = *resume
+		var err error
+		bucketPathsList, *ttl, err = loadResumableRequest(requestID)
+		if err != nil {
+			log.Fatalf("Failed to resume request %s: %v\n", requestID, err)
+		}
+		rlog(requestID).Info().Int("remaining_bucket_paths", len(bucketPathsList)).Msg("resuming request")
+	} else {
+		requestID = generateRequestID()
+		bucketPathsList = strings.Split(*bucketPaths, ",")
+
+		err = createDBAndRecord(requestID, bucketPathsList, *ttl, notifier)
+		if err != nil {
+			log.Fatalf("Failed to create DB record: %v\n", err)
+		}
 	}
 
 	var wg sync.WaitGroup
-	ch := make(chan struct{}, 5) // Limit to 5 concurrent routines
+	ch := make(chan struct{}, cc.PathConcurrency)
+	failedPathsCh := make(chan string, len(bucketPathsList))
 
 	for _, path := range bucketPathsList {
 		wg.Add(1)
-		go restoreObjectsInPath(path, *region, requestID, &failedPaths, &wg, ch)
+		if *backend == "batch" {
+			go restoreObjectsInPathBatch(context.Background(), path, *region, requestID, *tier, *ttl, batchCfg, cc, notifier, failedPathsCh, &wg, ch)
+		} else {
+			go restoreObjectsInPath(context.Background(), path, *region, requestID, *tier, *ttl, cc, notifier, failedPathsCh, &wg, ch)
+		}
 	}
 
 	wg.Wait()
+	close(failedPathsCh)
+	failedPaths := collectFailedPaths(failedPathsCh)
+
+	logURL, err := shipRequestLog(context.Background(), *region, *logS3, requestID)
+	if err != nil {
+		rlog(requestID).Error().Err(err).Msg("failed to ship log to S3")
+	}
 
 	if len(failedPaths) > 0 {
-		failedPathsJSON, _ := json.Marshal(failedPaths)
-		message := fmt.Sprintf(":x: *The following paths failed to be processed for Request ID:* *%s*\n*Failed Paths:* `%s`\n", requestID, failedPathsJSON)
-		if err := sendSlackNotification(os.Getenv("SLACK_CHANNEL_ID"), os.Getenv("SLACK_THREAD_TS"), []slack.Block{
-			slack.NewSectionBlock(&slack.TextBlockObject{
-				Type: slack.MarkdownType,
-				Text: message,
-			}, nil, nil),
-		}); err != nil {
-			log.Printf("Error sending Slack notification for failed paths: %v\n", err)
+		message := fmt.Sprintf("The following paths failed to be processed for Request ID: %s", requestID)
+		failureEvent := NotificationEvent{
+			RequestID: requestID,
+			Title:     ":x: " + message,
+			Fields:    logLinkFields(requestID, logURL),
+			Lists: []NotificationList{
+				{Label: "Failed Paths:", Items: failedPaths},
+			},
+			Request: RestoreRequest{
+				RequestID:   requestID,
+				BucketPaths: failedPaths,
+			},
+		}
+		if err := notifier.NotifyFailure(failureEvent); err != nil {
+			rlog(requestID).Error().Err(err).Msg("failed to send notification for failed paths")
+		}
+		rlog(requestID).Info().Msg(message)
+	} else {
+		completionEvent := NotificationEvent{
+			RequestID: requestID,
+			Title:     fmt.Sprintf(":white_check_mark: Restore process completed for Request ID: %s", requestID),
+			Fields:    logLinkFields(requestID, logURL),
+			Request:   RestoreRequest{RequestID: requestID},
+		}
+		if err := notifier.NotifyCompletion(completionEvent); err != nil {
+			rlog(requestID).Error().Err(err).Msg("failed to send completion notification")
 		}
-		log.Println(message)
 	}
 
-	fmt.Printf(":white_check_mark: *Restore process completed for Request ID:* *%s*\n", requestID)
-}
\ No newline at end of file
+	fmt.Printf(":white_check_mark: Restore process completed for Request ID: %s\n", requestID)
+	closeRequestLog(requestID)
+}
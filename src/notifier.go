@@ -0,0 +1,388 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// KV is a single labeled field rendered as "*Key:* `Value`" (or the
+// notifier-specific equivalent).
+type KV struct {
+	Key   string
+	Value string
+}
+
+// NotificationList is a labeled bullet list, e.g. the remaining or
+// processed bucket paths for a request.
+type NotificationList struct {
+	Label string
+	Items []string
+}
+
+// NotificationEvent carries everything a Notifier implementation needs to
+// render a progress, completion, or failure update without depending on any
+// one destination's message format.
+type NotificationEvent struct {
+	RequestID string
+	Title     string
+	Fields    []KV
+	Lists     []NotificationList
+	Request   RestoreRequest
+}
+
+// Notifier abstracts the destination a restore job reports progress to.
+// Implementations must be safe for concurrent use, since multiple restore
+// jobs can run in the same process.
+type Notifier interface {
+	NotifyProgress(event NotificationEvent) error
+	NotifyCompletion(event NotificationEvent) error
+	NotifyFailure(event NotificationEvent) error
+}
+
+// newNotifier builds a Notifier from a comma-separated --notifier spec such
+// as "slack,webhook". An empty spec falls back to the stdout notifier so the
+// tool still reports progress when run outside Slack (e.g. in CI).
+func newNotifier(spec string) (Notifier, error) {
+	var notifiers []Notifier
+	for _, kind := range strings.Split(spec, ",") {
+		switch strings.TrimSpace(strings.ToLower(kind)) {
+		case "":
+			continue
+		case "slack":
+			notifiers = append(notifiers, NewSlackNotifier())
+		case "teams":
+			notifiers = append(notifiers, NewTeamsNotifier())
+		case "webhook":
+			notifiers = append(notifiers, NewWebhookNotifier())
+		case "stdout":
+			notifiers = append(notifiers, StdoutNotifier{})
+		default:
+			return nil, fmt.Errorf("unknown notifier %q", kind)
+		}
+	}
+
+	if len(notifiers) == 0 {
+		return StdoutNotifier{}, nil
+	}
+	if len(notifiers) == 1 {
+		return notifiers[0], nil
+	}
+	return &MultiNotifier{notifiers: notifiers}, nil
+}
+
+// MultiNotifier fans a single event out to every configured notifier so
+// teams can wire up Slack and a webhook at the same time.
+type MultiNotifier struct {
+	notifiers []Notifier
+}
+
+func (m *MultiNotifier) dispatch(call func(Notifier) error) error {
+	var errs []string
+	for _, n := range m.notifiers {
+		if err := call(n); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notifier errors: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (m *MultiNotifier) NotifyProgress(event NotificationEvent) error {
+	return m.dispatch(func(n Notifier) error { return n.NotifyProgress(event) })
+}
+
+func (m *MultiNotifier) NotifyCompletion(event NotificationEvent) error {
+	return m.dispatch(func(n Notifier) error { return n.NotifyCompletion(event) })
+}
+
+func (m *MultiNotifier) NotifyFailure(event NotificationEvent) error {
+	return m.dispatch(func(n Notifier) error { return n.NotifyFailure(event) })
+}
+
+// SlackNotifier posts Block Kit messages to a channel/thread, updating the
+// same message in place once the first one is sent. messageTimestamps lives
+// on the struct (not a package global) and is keyed by RequestID so
+// concurrent restore jobs sharing one notifier each update their own message
+// instead of clobbering one another's.
+type SlackNotifier struct {
+	channel  string
+	threadTS string
+
+	mu                sync.Mutex
+	messageTimestamps map[string]string
+}
+
+func NewSlackNotifier() *SlackNotifier {
+	return &SlackNotifier{
+		channel:  os.Getenv("SLACK_CHANNEL_ID"),
+		threadTS: os.Getenv("SLACK_THREAD_TS"),
+	}
+}
+
+func (s *SlackNotifier) send(requestID string, blocks []slack.Block) error {
+	slackToken := os.Getenv("SLACK_API_TOKEN")
+	if slackToken == "" {
+		log.Println("No SLACK_API_TOKEN set. Slack messages will not be sent.")
+		return fmt.Errorf("SLACK_API_TOKEN is not set")
+	}
+
+	api := slack.New(slackToken)
+	opts := []slack.MsgOption{slack.MsgOptionBlocks(blocks...)}
+
+	if s.threadTS != "" {
+		opts = append(opts, slack.MsgOptionTS(s.threadTS))
+	}
+
+	s.mu.Lock()
+	existingTimestamp := s.messageTimestamps[requestID]
+	s.mu.Unlock()
+	if existingTimestamp != "" {
+		opts = append(opts, slack.MsgOptionUpdate(existingTimestamp))
+	}
+
+	_, newTimestamp, err := api.PostMessage(s.channel, opts...)
+	if err != nil {
+		rlog(requestID).Error().Err(err).Msg("failed to send Slack message")
+		return err
+	}
+
+	s.mu.Lock()
+	if s.messageTimestamps == nil {
+		s.messageTimestamps = make(map[string]string)
+	}
+	if s.messageTimestamps[requestID] == "" {
+		s.messageTimestamps[requestID] = newTimestamp
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func renderSlackBlocks(event NotificationEvent) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(&slack.TextBlockObject{
+			Type: slack.PlainTextType,
+			Text: event.Title,
+		}),
+	}
+
+	if len(event.Fields) > 0 {
+		var text strings.Builder
+		for _, field := range event.Fields {
+			if strings.HasPrefix(field.Value, "http://") || strings.HasPrefix(field.Value, "https://") {
+				text.WriteString(fmt.Sprintf("*%s:* <%s|link>\n", field.Key, field.Value))
+			} else {
+				text.WriteString(fmt.Sprintf("*%s:* `%s`\n", field.Key, field.Value))
+			}
+		}
+		blocks = append(blocks, slack.NewSectionBlock(
+			&slack.TextBlockObject{Type: slack.MarkdownType, Text: text.String()},
+			nil,
+			nil,
+		))
+	}
+
+	for _, list := range event.Lists {
+		blocks = append(blocks, slack.NewDividerBlock(), slack.NewSectionBlock(
+			&slack.TextBlockObject{Type: slack.MarkdownType, Text: fmt.Sprintf("*%s*", list.Label)},
+			nil,
+			nil,
+		))
+		for _, item := range list.Items {
+			blocks = append(blocks, slack.NewSectionBlock(
+				&slack.TextBlockObject{Type: slack.MarkdownType, Text: fmt.Sprintf("- `%s`", item)},
+				nil,
+				nil,
+			))
+		}
+	}
+
+	if event.RequestID != "" {
+		blocks = append(blocks, slack.NewActionBlock(
+			"restore_actions_"+event.RequestID,
+			slack.NewButtonBlockElement("cancel_restore", event.RequestID, &slack.TextBlockObject{
+				Type: slack.PlainTextType, Text: "Cancel",
+			}),
+			slack.NewButtonBlockElement("retry_failed_paths", event.RequestID, &slack.TextBlockObject{
+				Type: slack.PlainTextType, Text: "Retry Failed Paths",
+			}),
+		))
+	}
+
+	return blocks
+}
+
+func (s *SlackNotifier) NotifyProgress(event NotificationEvent) error {
+	return s.send(event.RequestID, renderSlackBlocks(event))
+}
+
+func (s *SlackNotifier) NotifyCompletion(event NotificationEvent) error {
+	return s.send(event.RequestID, renderSlackBlocks(event))
+}
+
+func (s *SlackNotifier) NotifyFailure(event NotificationEvent) error {
+	return s.send(event.RequestID, renderSlackBlocks(event))
+}
+
+// TeamsNotifier posts an Adaptive Card to a Microsoft Teams incoming
+// webhook, giving teams without Slack the same progress reporting.
+type TeamsNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func NewTeamsNotifier() *TeamsNotifier {
+	return &TeamsNotifier{
+		webhookURL: os.Getenv("TEAMS_WEBHOOK_URL"),
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func buildAdaptiveCard(event NotificationEvent) map[string]interface{} {
+	body := []map[string]interface{}{
+		{"type": "TextBlock", "text": event.Title, "weight": "Bolder", "size": "Medium", "wrap": true},
+	}
+
+	if len(event.Fields) > 0 {
+		facts := make([]map[string]string, 0, len(event.Fields))
+		for _, field := range event.Fields {
+			facts = append(facts, map[string]string{"title": field.Key, "value": field.Value})
+		}
+		body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+	}
+
+	for _, list := range event.Lists {
+		body = append(body,
+			map[string]interface{}{"type": "TextBlock", "text": list.Label, "weight": "Bolder", "wrap": true},
+			map[string]interface{}{"type": "TextBlock", "text": strings.Join(list.Items, "\n"), "wrap": true},
+		)
+	}
+
+	return map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    body,
+				},
+			},
+		},
+	}
+}
+
+func (t *TeamsNotifier) send(event NotificationEvent) error {
+	if t.webhookURL == "" {
+		return fmt.Errorf("TEAMS_WEBHOOK_URL is not set")
+	}
+
+	payload, err := json.Marshal(buildAdaptiveCard(event))
+	if err != nil {
+		return fmt.Errorf("failed to marshal adaptive card: %w", err)
+	}
+
+	resp, err := t.client.Post(t.webhookURL, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post Teams message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Teams webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (t *TeamsNotifier) NotifyProgress(event NotificationEvent) error   { return t.send(event) }
+func (t *TeamsNotifier) NotifyCompletion(event NotificationEvent) error { return t.send(event) }
+func (t *TeamsNotifier) NotifyFailure(event NotificationEvent) error    { return t.send(event) }
+
+// WebhookNotifier POSTs the raw RestoreRequest as JSON to a generic
+// endpoint, for teams that want to wire restore progress into their own
+// systems rather than a chat app.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    os.Getenv("WEBHOOK_URL"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (w *WebhookNotifier) send(event NotificationEvent) error {
+	if w.url == "" {
+		return fmt.Errorf("WEBHOOK_URL is not set")
+	}
+
+	payload, err := json.Marshal(event.Request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal restore request: %w", err)
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (w *WebhookNotifier) NotifyProgress(event NotificationEvent) error   { return w.send(event) }
+func (w *WebhookNotifier) NotifyCompletion(event NotificationEvent) error { return w.send(event) }
+func (w *WebhookNotifier) NotifyFailure(event NotificationEvent) error    { return w.send(event) }
+
+// StdoutNotifier prints plain-text progress to stdout. It has no external
+// dependencies, so CI runs and local testing get the same progress reports
+// without needing Slack or Teams credentials.
+type StdoutNotifier struct{}
+
+func renderPlainText(event NotificationEvent) string {
+	var text strings.Builder
+	text.WriteString(event.Title + "\n")
+	for _, field := range event.Fields {
+		text.WriteString(fmt.Sprintf("  %s: %s\n", field.Key, field.Value))
+	}
+	for _, list := range event.Lists {
+		text.WriteString(fmt.Sprintf("  %s\n", list.Label))
+		for _, item := range list.Items {
+			text.WriteString(fmt.Sprintf("    - %s\n", item))
+		}
+	}
+	return text.String()
+}
+
+func (StdoutNotifier) NotifyProgress(event NotificationEvent) error {
+	fmt.Print(renderPlainText(event))
+	return nil
+}
+
+func (StdoutNotifier) NotifyCompletion(event NotificationEvent) error {
+	fmt.Print(renderPlainText(event))
+	return nil
+}
+
+func (StdoutNotifier) NotifyFailure(event NotificationEvent) error {
+	fmt.Print(renderPlainText(event))
+	return nil
+}
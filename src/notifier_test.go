@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack"
+)
+
+func TestRenderSlackBlocksLinkifiesURLFields(t *testing.T) {
+	event := NotificationEvent{
+		RequestID: "req-1",
+		Title:     "Restore complete",
+		Fields: []KV{
+			{Key: "Request ID", Value: "req-1"},
+			{Key: "Log", Value: "https://example.com/log"},
+		},
+	}
+
+	blocks := renderSlackBlocks(event)
+
+	section, ok := blocks[1].(*slack.SectionBlock)
+	if !ok {
+		t.Fatalf("expected blocks[1] to be a SectionBlock, got %T", blocks[1])
+	}
+	rendered := section.Text.Text
+
+	if !strings.Contains(rendered, "<https://example.com/log|link>") {
+		t.Errorf("expected URL field to render as a link, got %s", rendered)
+	}
+	if !strings.Contains(rendered, "`req-1`") {
+		t.Errorf("expected non-URL field to render as a code span, got %s", rendered)
+	}
+}
+
+func TestRenderSlackBlocksAddsActionsForRequestID(t *testing.T) {
+	withID := renderSlackBlocks(NotificationEvent{RequestID: "req-1", Title: "Progress"})
+	withoutID := renderSlackBlocks(NotificationEvent{Title: "Progress"})
+
+	if len(withID) != len(withoutID)+1 {
+		t.Errorf("expected a RequestID to add exactly one action block, got %d blocks vs %d", len(withID), len(withoutID))
+	}
+}
+
+func TestBuildAdaptiveCardIncludesFieldsAndLists(t *testing.T) {
+	event := NotificationEvent{
+		Title:  "Restore complete",
+		Fields: []KV{{Key: "Request ID", Value: "req-1"}},
+		Lists:  []NotificationList{{Label: "Failed paths", Items: []string{"bucket/a"}}},
+	}
+
+	card := buildAdaptiveCard(event)
+
+	encoded, err := json.Marshal(card)
+	if err != nil {
+		t.Fatalf("failed to marshal adaptive card: %v", err)
+	}
+	rendered := string(encoded)
+
+	if !strings.Contains(rendered, "Request ID") || !strings.Contains(rendered, "req-1") {
+		t.Errorf("expected fact set with Request ID/req-1, got %s", rendered)
+	}
+	if !strings.Contains(rendered, "Failed paths") || !strings.Contains(rendered, "bucket/a") {
+		t.Errorf("expected list label and item, got %s", rendered)
+	}
+}
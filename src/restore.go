@@ -0,0 +1,349 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"golang.org/x/time/rate"
+)
+
+// restorePollInterval controls how often HeadObject is polled while a
+// Glacier/Deep Archive restore job is in flight.
+const restorePollInterval = 30 * time.Second
+
+// RestoreObjectState tracks the lifecycle of a single object's Glacier
+// restore so that --resume can pick up in-flight jobs instead of
+// re-submitting RestoreObject requests that are already running.
+type RestoreObjectState struct {
+	RequestID   string
+	Bucket      string
+	Key         string
+	Tier        string
+	SubmittedAt string
+	ExpiresAt   string
+	Status      string
+}
+
+func ensureRestoreObjectsTable(db *sql.DB) error {
+	createTableQuery := `
+	CREATE TABLE IF NOT EXISTS restore_objects (
+		request_id TEXT,
+		bucket TEXT,
+		key TEXT,
+		tier TEXT,
+		submitted_at TEXT,
+		expires_at TEXT,
+		status TEXT,
+		PRIMARY KEY (request_id, bucket, key)
+	)`
+	_, err := db.Exec(createTableQuery)
+	if err != nil {
+		return fmt.Errorf("failed to create restore_objects table: %w", err)
+	}
+	return nil
+}
+
+// recordRestoreObjectSubmitted upserts a restore_objects row right after
+// RestoreObject has been accepted by S3, so a crash between submission and
+// completion is still resumable.
+func recordRestoreObjectSubmitted(requestID, bucket, key, tier string, ttlDays int) error {
+	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := ensureRestoreObjectsTable(db); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.AddDate(0, 0, ttlDays)
+
+	upsertQuery := `
+	INSERT INTO restore_objects (request_id, bucket, key, tier, submitted_at, expires_at, status)
+	VALUES (?, ?, ?, ?, ?, ?, 'ongoing')
+	ON CONFLICT(request_id, bucket, key) DO UPDATE SET
+		tier = excluded.tier,
+		submitted_at = excluded.submitted_at,
+		expires_at = excluded.expires_at,
+		status = 'ongoing'`
+	_, err = db.Exec(upsertQuery, requestID, bucket, key, tier, now.Format(time.RFC3339), expiresAt.Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to record restore object state: %w", err)
+	}
+	return nil
+}
+
+func updateRestoreObjectStatus(requestID, bucket, key, status string) error {
+	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	updateQuery := `
+	UPDATE restore_objects SET status = ? WHERE request_id = ? AND bucket = ? AND key = ?`
+	_, err = db.Exec(updateQuery, status, requestID, bucket, key)
+	if err != nil {
+		return fmt.Errorf("failed to update restore object status: %w", err)
+	}
+	return nil
+}
+
+// restoreObjectState looks up a previously submitted restore so --resume can
+// decide whether to re-submit RestoreObject or jump straight to polling.
+func restoreObjectState(requestID, bucket, key string) (*RestoreObjectState, error) {
+	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if err := ensureRestoreObjectsTable(db); err != nil {
+		return nil, err
+	}
+
+	state := &RestoreObjectState{RequestID: requestID, Bucket: bucket, Key: key}
+	selectQuery := `
+	SELECT tier, submitted_at, expires_at, status FROM restore_objects
+	WHERE request_id = ? AND bucket = ? AND key = ?`
+	err = db.QueryRow(selectQuery, requestID, bucket, key).Scan(&state.Tier, &state.SubmittedAt, &state.ExpiresAt, &state.Status)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load restore object state: %w", err)
+	}
+	return state, nil
+}
+
+// loadResumableRequest reads the remaining bucket paths and TTL for a
+// previously created restore_requests row so --resume can continue a run
+// without re-listing or re-submitting already-completed paths.
+func loadResumableRequest(requestID string) ([]string, int, error) {
+	db, err := sql.Open("sqlite3", "./s3_restore_requests.db")
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	var bucketPathsJSON string
+	var ttl int
+	selectQuery := "SELECT bucket_paths, ttl FROM restore_requests WHERE request_id = ?"
+	err = db.QueryRow(selectQuery, requestID).Scan(&bucketPathsJSON, &ttl)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find request %s: %w", requestID, err)
+	}
+
+	var bucketPaths []string
+	if err := json.Unmarshal([]byte(bucketPathsJSON), &bucketPaths); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse bucket paths for request %s: %w", requestID, err)
+	}
+
+	if len(bucketPaths) == 0 {
+		return nil, 0, fmt.Errorf("request %s has no remaining bucket paths to resume", requestID)
+	}
+
+	return bucketPaths, ttl, nil
+}
+
+// validateTier rejects any --tier value other than the three
+// GlacierJobParameters.Tier accepts (Bulk, Standard, Expedited), so a typo
+// fails fast at startup instead of one RestoreObject call at a time deep
+// into a run.
+func validateTier(tier string) error {
+	switch types.Tier(tier) {
+	case types.TierBulk, types.TierStandard, types.TierExpedited:
+		return nil
+	default:
+		return fmt.Errorf("invalid --tier %q: must be one of Bulk, Standard, Expedited", tier)
+	}
+}
+
+// needsGlacierRestore reports whether sc requires the two-phase
+// RestoreObject/poll/CopyObject flow. S3 rejects RestoreObject with
+// InvalidObjectState for any other storage class, so callers must fall back
+// to a plain CopyObject for STANDARD_IA/ONEZONE_IA/INTELLIGENT_TIERING/
+// REDUCED_REDUNDANCY objects instead of routing them through here.
+func needsGlacierRestore(sc types.ObjectStorageClass) bool {
+	switch sc {
+	case types.ObjectStorageClassGlacier, types.ObjectStorageClassDeepArchive, types.ObjectStorageClassGlacierIr:
+		return true
+	default:
+		return false
+	}
+}
+
+// copyObjectToStandard transitions an already-readable non-STANDARD object
+// (STANDARD_IA, ONEZONE_IA, INTELLIGENT_TIERING, REDUCED_REDUNDANCY) back to
+// STANDARD storage with a plain CopyObject, skipping the RestoreObject/poll
+// phases those classes don't need.
+func copyObjectToStandard(ctx context.Context, svc *s3.Client, requestID, bucketName, key string, limiter *rate.Limiter) error {
+	copyStart := time.Now()
+	copyInput := &s3.CopyObjectInput{
+		Bucket:       aws.String(bucketName),
+		CopySource:   aws.String(fmt.Sprintf("%s/%s", bucketName, key)),
+		Key:          aws.String(key),
+		StorageClass: types.StorageClassStandard,
+	}
+	if err := withRetry(ctx, requestID, limiter, func() error {
+		_, err := svc.CopyObject(ctx, copyInput)
+		return err
+	}); err != nil {
+		logPhase(requestID, bucketName, key, "copy", copyStart, err)
+		return fmt.Errorf("failed to transition object %s to STANDARD: %w", key, err)
+	}
+	logPhase(requestID, bucketName, key, "copy", copyStart, nil)
+	return nil
+}
+
+// submitGlacierRestore issues the RestoreObject half of a Glacier/Deep
+// Archive restore (Days = ttlDays, tier as requested) and records the
+// submission so --resume can pick polling back up without resubmitting. If
+// a restore for this key was already submitted (tracked in
+// restore_objects, or reported by S3 as already in progress), submission is
+// skipped. It returns as soon as the request is accepted; waiting for the
+// restore to finish and copying the object back to STANDARD happens in
+// finishGlacierRestore, kept separate so a multi-hour Bulk/Standard
+// retrieval doesn't hold an object-concurrency slot for its whole lifetime.
+// Every S3 call waits on limiter and retries with backoff if S3 throttles it.
+func submitGlacierRestore(ctx context.Context, svc *s3.Client, requestID, bucketName, key, tier string, ttlDays int, limiter *rate.Limiter) error {
+	existing, err := restoreObjectState(requestID, bucketName, key)
+	if err != nil {
+		rlog(requestID).Error().Err(err).Str("bucket", bucketName).Str("key", key).Msg("failed to load restore state")
+	}
+
+	if existing != nil && existing.Status != "" {
+		rlog(requestID).Info().Str("bucket", bucketName).Str("key", key).Str("status", existing.Status).Msg("resuming existing restore job")
+		return nil
+	}
+
+	submitStart := time.Now()
+
+	restoreInput := &s3.RestoreObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+		RestoreRequest: &types.RestoreRequest{
+			Days: aws.Int32(int32(ttlDays)),
+			GlacierJobParameters: &types.GlacierJobParameters{
+				Tier: types.Tier(tier),
+			},
+		},
+	}
+
+	err = withRetry(ctx, requestID, limiter, func() error {
+		_, err := svc.RestoreObject(ctx, restoreInput)
+		return err
+	})
+	if err != nil && !strings.Contains(err.Error(), "RestoreAlreadyInProgress") {
+		logPhase(requestID, bucketName, key, "restore_submit", submitStart, err)
+		return fmt.Errorf("failed to submit restore for object %s: %w", key, err)
+	}
+	logPhase(requestID, bucketName, key, "restore_submit", submitStart, nil)
+
+	if err := recordRestoreObjectSubmitted(requestID, bucketName, key, tier, ttlDays); err != nil {
+		rlog(requestID).Error().Err(err).Str("bucket", bucketName).Str("key", key).Msg("failed to persist restore object state")
+	}
+	return nil
+}
+
+// finishGlacierRestore polls until a previously submitted Glacier restore
+// completes, then permanently transitions the object to STANDARD storage
+// with CopyObject and verifies the result.
+func finishGlacierRestore(ctx context.Context, svc *s3.Client, requestID, bucketName, key string, limiter *rate.Limiter) error {
+	pollStart := time.Now()
+	if err := waitForRestoreCompletion(ctx, requestID, svc, bucketName, key, limiter); err != nil {
+		logPhase(requestID, bucketName, key, "restore_poll", pollStart, err)
+		return err
+	}
+	logPhase(requestID, bucketName, key, "restore_poll", pollStart, nil)
+
+	if err := updateRestoreObjectStatus(requestID, bucketName, key, "restored"); err != nil {
+		rlog(requestID).Error().Err(err).Str("bucket", bucketName).Str("key", key).Msg("failed to mark restore object as restored")
+	}
+
+	copyStart := time.Now()
+	copyInput := &s3.CopyObjectInput{
+		Bucket:       aws.String(bucketName),
+		CopySource:   aws.String(fmt.Sprintf("%s/%s", bucketName, key)),
+		Key:          aws.String(key),
+		StorageClass: types.StorageClassStandard,
+	}
+	if err := withRetry(ctx, requestID, limiter, func() error {
+		_, err := svc.CopyObject(ctx, copyInput)
+		return err
+	}); err != nil {
+		logPhase(requestID, bucketName, key, "copy", copyStart, err)
+		return fmt.Errorf("failed to transition object %s to STANDARD: %w", key, err)
+	}
+
+	var headOutput *s3.HeadObjectOutput
+	if err := withRetry(ctx, requestID, limiter, func() error {
+		var err error
+		headOutput, err = svc.HeadObject(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(key),
+		})
+		return err
+	}); err != nil {
+		logPhase(requestID, bucketName, key, "copy", copyStart, err)
+		return fmt.Errorf("failed to verify storage class for object %s: %w", key, err)
+	}
+	if headOutput.StorageClass == "" || headOutput.StorageClass != types.StorageClassStandard {
+		err := fmt.Errorf("storage class for object %s is not STANDARD, it is %v", key, headOutput.StorageClass)
+		logPhase(requestID, bucketName, key, "copy", copyStart, err)
+		return err
+	}
+	logPhase(requestID, bucketName, key, "copy", copyStart, nil)
+
+	if err := updateRestoreObjectStatus(requestID, bucketName, key, "completed"); err != nil {
+		rlog(requestID).Error().Err(err).Str("bucket", bucketName).Str("key", key).Msg("failed to mark restore object as completed")
+	}
+
+	return nil
+}
+
+// waitForRestoreCompletion polls HeadObject until S3 reports the temporary
+// restored copy is ready (Restore: ongoing-request="false").
+func waitForRestoreCompletion(ctx context.Context, requestID string, svc *s3.Client, bucketName, key string, limiter *rate.Limiter) error {
+	for {
+		var headOutput *s3.HeadObjectOutput
+		err := withRetry(ctx, requestID, limiter, func() error {
+			var err error
+			headOutput, err = svc.HeadObject(ctx, &s3.HeadObjectInput{
+				Bucket: aws.String(bucketName),
+				Key:    aws.String(key),
+			})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check restore status for object %s: %w", key, err)
+		}
+
+		if headOutput.Restore == nil {
+			return fmt.Errorf("object %s has no restore in progress", key)
+		}
+
+		if strings.Contains(*headOutput.Restore, `ongoing-request="false"`) {
+			return nil
+		}
+
+		rlog(requestID).Info().Str("bucket", bucketName).Str("key", key).
+			Dur("poll_interval_ms", restorePollInterval).
+			Msg("restore still in progress")
+
+		select {
+		case <-time.After(restorePollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
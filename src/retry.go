@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"golang.org/x/time/rate"
+)
+
+// retryMaxAttempts bounds how many times in total withRetry will attempt a
+// throttled S3 call before giving up and surfacing the last error to the
+// caller.
+const (
+	retryMaxAttempts = 5
+	retryBaseDelay   = 200 * time.Millisecond
+	retryMaxDelay    = 30 * time.Second
+)
+
+// throttlingErrorCodes are the S3/S3 Control error codes the AWS SDK
+// surfaces when a request is rejected for exceeding the account or prefix's
+// request rate, as opposed to a real failure worth giving up on.
+var throttlingErrorCodes = map[string]bool{
+	"RequestLimitExceeded":     true,
+	"SlowDown":                 true,
+	"Throttling":               true,
+	"ThrottlingException":      true,
+	"TooManyRequestsException": true,
+}
+
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return throttlingErrorCodes[apiErr.ErrorCode()]
+	}
+	return false
+}
+
+// withRetry blocks on limiter (so the configured --rps cap is respected even
+// on the first attempt), then runs op. If op fails with one of
+// throttlingErrorCodes, it retries with exponential backoff and full jitter
+// up to retryMaxAttempts before giving up.
+func withRetry(ctx context.Context, requestID string, limiter *rate.Limiter, op func() error) error {
+	var lastErr error
+	for attempt := 0; attempt < retryMaxAttempts; attempt++ {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		lastErr = op()
+		if lastErr == nil {
+			return nil
+		}
+		if !isThrottlingError(lastErr) {
+			return lastErr
+		}
+
+		delay := retryBackoff(attempt)
+		rlog(requestID).Error().Err(lastErr).Int("attempt", attempt+1).Dur("retry_in_ms", delay).Msg("S3 call throttled, retrying")
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return fmt.Errorf("gave up after %d attempts: %w", retryMaxAttempts, lastErr)
+}
+
+// retryBackoff returns an exponential delay for attempt (0-indexed), capped
+// at retryMaxDelay and jittered over its full range so a burst of throttled
+// callers don't retry in lockstep.
+func retryBackoff(attempt int) time.Duration {
+	delay := retryBaseDelay * time.Duration(1<<uint(attempt))
+	if delay > retryMaxDelay || delay <= 0 {
+		delay = retryMaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay)))
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestIsThrottlingError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"throttling code", &smithy.GenericAPIError{Code: "SlowDown"}, true},
+		{"another throttling code", &smithy.GenericAPIError{Code: "RequestLimitExceeded"}, true},
+		{"non-throttling api error", &smithy.GenericAPIError{Code: "AccessDenied"}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isThrottlingError(c.err); got != c.want {
+				t.Errorf("isThrottlingError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRetryBackoffStaysWithinBounds(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		delay := retryBackoff(attempt)
+		if delay < 0 || delay > retryMaxDelay {
+			t.Errorf("retryBackoff(%d) = %v, want within [0, %v]", attempt, delay, retryMaxDelay)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+)
+
+// runServeCommand parses `serve` subcommand flags and starts the
+// long-running Slack bot. Unlike the one-shot restore flow, `serve` never
+// returns on its own - it keeps the Socket Mode connection open until
+// killed.
+func runServeCommand(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	region := fs.String("region", "", "AWS region for restore jobs submitted via Slack")
+	tier := fs.String("tier", "Standard", "Glacier restore tier to request (Bulk, Standard, or Expedited)")
+	profile := fs.String("profile", "default", "AWS profile to use")
+	notifierSpec := fs.String("notifier", "slack", "Comma-separated notification sinks to use (slack, teams, webhook, stdout)")
+	pathConcurrency := fs.Int("path-concurrency", 5, "Number of bucket paths to process concurrently")
+	objectConcurrency := fs.Int("object-concurrency", 5, "Number of objects to restore concurrently within a single bucket path")
+	rps := fs.Float64("rps", 50, "Maximum combined S3 requests per second across all in-flight paths/objects; 0 disables the limit")
+	fs.Parse(args)
+
+	if *region == "" {
+		log.Fatal("Please provide an AWS region")
+	}
+	if err := validateTier(*tier); err != nil {
+		log.Fatal(err)
+	}
+
+	notifier, err := newNotifier(*notifierSpec)
+	if err != nil {
+		log.Fatalf("Failed to configure notifier: %v", err)
+	}
+
+	if err := initCredentials(*profile, *region); err != nil {
+		log.Fatal(err)
+	}
+
+	cc := NewConcurrencyConfig(*pathConcurrency, *objectConcurrency, *rps)
+
+	if err := RunServe(*region, *tier, cc, notifier); err != nil {
+		log.Fatalf("Slack bot exited: %v", err)
+	}
+}
+
+// RunServe opens a Slack Socket Mode connection and dispatches
+// `/s3-restore` slash commands and Block Kit button actions (Cancel, Retry
+// Failed Paths) to a BotServer for as long as the process runs.
+func RunServe(region, tier string, cc ConcurrencyConfig, notifier Notifier) error {
+	botToken := os.Getenv("SLACK_API_TOKEN")
+	appToken := os.Getenv("SLACK_APP_TOKEN")
+	if botToken == "" || appToken == "" {
+		return fmt.Errorf("SLACK_API_TOKEN and SLACK_APP_TOKEN must both be set to run serve")
+	}
+
+	api := slack.New(botToken, slack.OptionAppLevelToken(appToken))
+	client := socketmode.New(api)
+	bot := NewBotServer(region, tier, cc, notifier)
+
+	go func() {
+		for evt := range client.Events {
+			switch evt.Type {
+			case socketmode.EventTypeSlashCommand:
+				cmd, ok := evt.Data.(slack.SlashCommand)
+				if !ok {
+					continue
+				}
+				client.Ack(*evt.Request)
+
+				reply := bot.Dispatch(context.Background(), cmd.Text)
+				if _, _, err := api.PostMessage(cmd.ChannelID, slack.MsgOptionText(reply, false)); err != nil {
+					log.Printf("Failed to reply to slash command: %v\n", err)
+				}
+
+			case socketmode.EventTypeInteractive:
+				callback, ok := evt.Data.(slack.InteractionCallback)
+				if !ok {
+					continue
+				}
+				client.Ack(*evt.Request)
+				handleBlockActions(bot, callback)
+			}
+		}
+	}()
+
+	log.Println("s3-restore bot running in Socket Mode")
+	return client.Run()
+}
+
+// handleBlockActions responds to the Cancel and Retry Failed Paths buttons
+// attached to progress messages by renderSlackBlocks; the button value is
+// the RequestID of the job it belongs to.
+func handleBlockActions(bot *BotServer, callback slack.InteractionCallback) {
+	for _, action := range callback.ActionCallback.BlockActions {
+		requestID := action.Value
+
+		switch action.ActionID {
+		case "cancel_restore":
+			bot.jobs.Cancel(requestID)
+
+		case "retry_failed_paths":
+			job, ok := bot.jobs.Get(requestID)
+			if !ok {
+				continue
+			}
+			failedPaths := job.FailedPaths()
+			if len(failedPaths) == 0 {
+				continue
+			}
+			if _, err := bot.jobs.Submit(context.Background(), bot.region, bot.tier, job.TTL, failedPaths, bot.concurrency, bot.notifier); err != nil {
+				rlog(requestID).Error().Err(err).Msg("failed to retry failed paths")
+			}
+		}
+	}
+}